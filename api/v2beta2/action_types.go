@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Install holds the configuration for Helm install actions performed for
+// this HelmRelease.
+type Install struct {
+	// Timeout is the time to wait for any individual Kubernetes operation
+	// during the performance of a Helm install action. Defaults to the
+	// global Timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Remediation holds the remediation configuration used when the
+	// installation of the Helm release fails.
+	// +optional
+	Remediation *InstallRemediation `json:"remediation,omitempty"`
+}
+
+// GetTimeout returns the configured Timeout, or the given default.
+func (in Install) GetTimeout(def metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return def
+	}
+	return *in.Timeout
+}
+
+// GetRemediation returns the configured Remediation configuration, or a
+// default.
+func (in Install) GetRemediation() *Remediation {
+	if in.Remediation == nil {
+		return &Remediation{}
+	}
+	return (*Remediation)(in.Remediation)
+}
+
+// InstallRemediation is an alias of Remediation, kept separate so install
+// and upgrade remediation can diverge in the future without an API break.
+type InstallRemediation Remediation
+
+// Upgrade holds the configuration for Helm upgrade actions performed for
+// this HelmRelease.
+type Upgrade struct {
+	// Timeout is the time to wait for any individual Kubernetes operation
+	// during the performance of a Helm upgrade action. Defaults to the
+	// global Timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Remediation holds the remediation configuration used when the upgrade
+	// of the Helm release fails.
+	// +optional
+	Remediation *UpgradeRemediation `json:"remediation,omitempty"`
+}
+
+// GetTimeout returns the configured Timeout, or the given default.
+func (in Upgrade) GetTimeout(def metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return def
+	}
+	return *in.Timeout
+}
+
+// GetRemediation returns the configured Remediation configuration, or a
+// default.
+func (in Upgrade) GetRemediation() *Remediation {
+	if in.Remediation == nil {
+		return &Remediation{}
+	}
+	return (*Remediation)(in.Remediation)
+}
+
+// UpgradeRemediation is an alias of Remediation, kept separate so install
+// and upgrade remediation can diverge in the future without an API break.
+type UpgradeRemediation Remediation
+
+// Test holds the configuration for Helm test actions performed for this
+// HelmRelease.
+type Test struct {
+	// Timeout is the time to wait for any individual Kubernetes operation
+	// during the performance of a Helm test action. Defaults to the global
+	// Timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Enable enables running Helm tests after every Helm install/upgrade
+	// action.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+
+	// IgnoreFailures tells the controller to skip remediation when the Helm
+	// tests are run but fail, and to proceed as if the tests succeeded.
+	// +optional
+	IgnoreFailures bool `json:"ignoreFailures,omitempty"`
+}
+
+// GetTimeout returns the configured Timeout, or the given default.
+func (in Test) GetTimeout(def metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return def
+	}
+	return *in.Timeout
+}
+
+// Rollback holds the configuration for Helm rollback actions performed for
+// this HelmRelease.
+type Rollback struct {
+	// Timeout is the time to wait for any individual Kubernetes operation
+	// during the performance of a Helm rollback action. Defaults to the
+	// global Timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GetTimeout returns the configured Timeout, or the given default.
+func (in Rollback) GetTimeout(def metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return def
+	}
+	return *in.Timeout
+}
+
+// Uninstall holds the configuration for Helm uninstall actions performed for
+// this HelmRelease.
+type Uninstall struct {
+	// Timeout is the time to wait for any individual Kubernetes operation
+	// during the performance of a Helm uninstall action. Defaults to the
+	// global Timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GetTimeout returns the configured Timeout, or the given default.
+func (in Uninstall) GetTimeout(def metav1.Duration) metav1.Duration {
+	if in.Timeout == nil {
+		return def
+	}
+	return *in.Timeout
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+const (
+	// ReleasedCondition represents the status of the last release attempt
+	// (install/upgrade/test) against the latest desired state.
+	ReleasedCondition string = "Released"
+
+	// RemediatedCondition represents the status of the last remediation
+	// attempt (rollback/uninstall) due to a failure of the last release
+	// attempt against the latest desired state.
+	RemediatedCondition string = "Remediated"
+
+	// TestSuccessCondition represents the status of the last test attempt
+	// against the latest desired state.
+	TestSuccessCondition string = "TestSuccess"
+)
+
+const (
+	// PendingReleaseTimeoutReason signals that a release remained in a
+	// pending-install, pending-upgrade or pending-rollback Helm status for
+	// longer than the configured spec.pendingReleaseTimeout, and was marked
+	// as failed by the controller as a result.
+	PendingReleaseTimeoutReason string = "PendingReleaseTimeout"
+)
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+// DriftDetectionMode represents the mode of drift detection, and what
+// should be done about any detected drift.
+type DriftDetectionMode string
+
+const (
+	// DriftDetectionEnabled enables drift detection, and runs a full Helm
+	// upgrade action (re-running hooks and re-rendering the chart) to
+	// correct any detected drift.
+	DriftDetectionEnabled DriftDetectionMode = "enabled"
+
+	// DriftDetectionDisabled disables drift detection entirely.
+	DriftDetectionDisabled DriftDetectionMode = "disabled"
+
+	// DriftDetectionWarn enables drift detection, but only warns about any
+	// detected drift through events and conditions, without taking any
+	// corrective action.
+	DriftDetectionWarn DriftDetectionMode = "warn"
+
+	// DriftCorrectionModeSSA enables drift detection, and corrects any
+	// detected drift by server-side applying the full desired state of only
+	// the affected objects, without running a full Helm upgrade.
+	DriftCorrectionModeSSA DriftDetectionMode = "ssa"
+)
+
+// DriftDetection holds the configuration for detecting and handling
+// differences between the manifest in the Helm storage and the resources on
+// the cluster.
+type DriftDetection struct {
+	// Mode defines how differences between the manifest in the Helm storage
+	// and the resources on the cluster are handled. Defaults to
+	// DriftDetectionEnabled.
+	// +kubebuilder:validation:Enum=enabled;disabled;warn;ssa
+	// +optional
+	Mode DriftDetectionMode `json:"mode,omitempty"`
+}
+
+// GetMode returns the configured Mode, or DriftDetectionEnabled if unset.
+func (in DriftDetection) GetMode() DriftDetectionMode {
+	if in.Mode == "" {
+		return DriftDetectionEnabled
+	}
+	return in.Mode
+}
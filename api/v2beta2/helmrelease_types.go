@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultPendingReleaseTimeout is the amount of time a release is allowed to
+// remain in a pending-install, pending-upgrade or pending-rollback Helm
+// status before it is considered stuck, used when a HelmRelease does not
+// specify spec.pendingReleaseTimeout.
+const defaultPendingReleaseTimeout = 5 * time.Minute
+
+// HelmRelease is the Schema for the helmreleases API.
+type HelmRelease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HelmReleaseSpec   `json:"spec,omitempty"`
+	Status HelmReleaseStatus `json:"status,omitempty"`
+}
+
+// HelmReleaseList contains a list of HelmRelease.
+type HelmReleaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HelmRelease `json:"items"`
+}
+
+// HelmReleaseSpec defines the desired state of a Helm release.
+type HelmReleaseSpec struct {
+	// Suspend tells the controller to suspend reconciliation for this
+	// HelmRelease.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Timeout is the time to wait for any individual Kubernetes operation
+	// (like Jobs for hooks) during the performance of a Helm action. Defaults
+	// to '5m0s'.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// PendingReleaseTimeout is the amount of time to wait for a pending
+	// Helm release (pending-install, pending-upgrade or pending-rollback) to
+	// complete before it is considered stuck. Once exceeded, a MarkFailed
+	// action is run so that the configured remediation strategy can recover
+	// it. Defaults to DefaultPendingReleaseTimeout.
+	// +optional
+	PendingReleaseTimeout *metav1.Duration `json:"pendingReleaseTimeout,omitempty"`
+
+	// Install holds the configuration for Helm install actions for this
+	// HelmRelease.
+	// +optional
+	Install *Install `json:"install,omitempty"`
+
+	// Upgrade holds the configuration for Helm upgrade actions for this
+	// HelmRelease.
+	// +optional
+	Upgrade *Upgrade `json:"upgrade,omitempty"`
+
+	// Test holds the configuration for Helm test actions for this
+	// HelmRelease.
+	// +optional
+	Test *Test `json:"test,omitempty"`
+
+	// Rollback holds the configuration for Helm rollback actions for this
+	// HelmRelease.
+	// +optional
+	Rollback *Rollback `json:"rollback,omitempty"`
+
+	// Uninstall holds the configuration for Helm uninstall actions for this
+	// HelmRelease.
+	// +optional
+	Uninstall *Uninstall `json:"uninstall,omitempty"`
+
+	// DriftDetection holds the configuration for detecting and handling
+	// differences between the manifest in the Helm storage and the resources
+	// on the cluster.
+	// +optional
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+}
+
+// HelmReleaseStatus defines the observed state of a HelmRelease.
+type HelmReleaseStatus struct {
+	// ObservedGeneration is the last observed generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastAttemptedReleaseAction is the last release action performed for
+	// this HelmRelease. It is used to determine the active remediation
+	// strategy.
+	// +optional
+	LastAttemptedReleaseAction string `json:"lastAttemptedReleaseAction,omitempty"`
+
+	// LastAppliedRevision is the revision of the last successfully applied
+	// source.
+	// Deprecated: use History instead.
+	// +optional
+	LastAppliedRevision string `json:"lastAppliedRevision,omitempty"`
+
+	// History holds the history of Helm releases performed for this
+	// HelmRelease up to the last successfully completed release.
+	// +optional
+	History Snapshots `json:"history,omitempty"`
+
+	// InstallFailures is the number of sequential install failures observed
+	// for the current generation.
+	// +optional
+	InstallFailures int64 `json:"installFailures,omitempty"`
+
+	// UpgradeFailures is the number of sequential upgrade failures observed
+	// for the current generation.
+	// +optional
+	UpgradeFailures int64 `json:"upgradeFailures,omitempty"`
+
+	// Conditions holds the conditions for the HelmRelease.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GetConditions returns the status conditions of the object.
+func (in *HelmRelease) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the status conditions on the object.
+func (in *HelmRelease) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+// GetTimeout returns the configured Timeout, or '5m0s' if unset.
+func (in HelmRelease) GetTimeout() metav1.Duration {
+	if in.Spec.Timeout == nil {
+		return metav1.Duration{Duration: 5 * time.Minute}
+	}
+	return *in.Spec.Timeout
+}
+
+// GetPendingReleaseTimeout returns the configured PendingReleaseTimeout. If
+// unset, it falls back to def (e.g. action.ConfigFactory's
+// DefaultPendingReleaseTimeout), and if def is the zero value, to
+// defaultPendingReleaseTimeout.
+func (in HelmRelease) GetPendingReleaseTimeout(def metav1.Duration) metav1.Duration {
+	if in.Spec.PendingReleaseTimeout != nil {
+		return *in.Spec.PendingReleaseTimeout
+	}
+	if def.Duration > 0 {
+		return def
+	}
+	return metav1.Duration{Duration: defaultPendingReleaseTimeout}
+}
+
+// GetInstall returns the configured Install, or a default.
+func (in HelmRelease) GetInstall() Install {
+	if in.Spec.Install == nil {
+		return Install{}
+	}
+	return *in.Spec.Install
+}
+
+// GetUpgrade returns the configured Upgrade, or a default.
+func (in HelmRelease) GetUpgrade() Upgrade {
+	if in.Spec.Upgrade == nil {
+		return Upgrade{}
+	}
+	return *in.Spec.Upgrade
+}
+
+// GetTest returns the configured Test, or a default.
+func (in HelmRelease) GetTest() Test {
+	if in.Spec.Test == nil {
+		return Test{}
+	}
+	return *in.Spec.Test
+}
+
+// GetRollback returns the configured Rollback, or a default.
+func (in HelmRelease) GetRollback() Rollback {
+	if in.Spec.Rollback == nil {
+		return Rollback{}
+	}
+	return *in.Spec.Rollback
+}
+
+// GetUninstall returns the configured Uninstall, or a default.
+func (in HelmRelease) GetUninstall() Uninstall {
+	if in.Spec.Uninstall == nil {
+		return Uninstall{}
+	}
+	return *in.Spec.Uninstall
+}
+
+// GetDriftDetection returns the configured DriftDetection, or a default.
+func (in HelmRelease) GetDriftDetection() DriftDetection {
+	if in.Spec.DriftDetection == nil {
+		return DriftDetection{}
+	}
+	return *in.Spec.DriftDetection
+}
+
+// GetActiveRemediation returns the active Remediation configuration for the
+// last attempted release action (a default if none is configured), or nil if
+// the last attempted release action is neither "install" nor "upgrade".
+func (in HelmRelease) GetActiveRemediation() *Remediation {
+	switch in.Status.LastAttemptedReleaseAction {
+	case "install":
+		return in.GetInstall().GetRemediation()
+	case "upgrade":
+		return in.GetUpgrade().GetRemediation()
+	default:
+		return nil
+	}
+}
+
+// ClearHistory clears the History of the HelmReleaseStatus.
+func (in *HelmReleaseStatus) ClearHistory() {
+	in.History = nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-helm-toolkit-fluxcd-io-v2beta2-helmrelease,mutating=false,failurePolicy=fail,groups=helm.toolkit.fluxcd.io,resources=helmreleases,versions=v2beta2,name=vhelmrelease.kb.io,sideEffects=None,admissionReviewVersions=v1
+
+var _ admission.Validator = &HelmRelease{}
+
+// ValidateCreate implements admission.Validator.
+func (in *HelmRelease) ValidateCreate() (admission.Warnings, error) {
+	return nil, in.validate()
+}
+
+// ValidateUpdate implements admission.Validator.
+func (in *HelmRelease) ValidateUpdate(_ runtime.Object) (admission.Warnings, error) {
+	return nil, in.validate()
+}
+
+// ValidateDelete implements admission.Validator.
+func (in *HelmRelease) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the fields of the HelmRelease which cannot be expressed
+// through static CRD validation, such as RemediationStrategy: it is an open
+// string type whose valid values depend on which strategies the running
+// controller has registered (see RegisterRemediationStrategy), so they can
+// only be checked here rather than through a CRD enum.
+func (in *HelmRelease) validate() error {
+	if err := validateRemediation(in.GetInstall().GetRemediation()); err != nil {
+		return fmt.Errorf("spec.install.remediation: %w", err)
+	}
+	if err := validateRemediation(in.GetUpgrade().GetRemediation()); err != nil {
+		return fmt.Errorf("spec.upgrade.remediation: %w", err)
+	}
+	return nil
+}
+
+func validateRemediation(r *Remediation) error {
+	if r == nil || r.Strategy == nil {
+		return nil
+	}
+	if !IsValidRemediationStrategy(*r.Strategy) {
+		return fmt.Errorf("unsupported strategy %q", *r.Strategy)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func helmReleaseWithUpgradeStrategy(strategy RemediationStrategy) *HelmRelease {
+	return &HelmRelease{
+		Spec: HelmReleaseSpec{
+			Upgrade: &Upgrade{
+				Remediation: &UpgradeRemediation{
+					Strategy: &strategy,
+				},
+			},
+		},
+	}
+}
+
+func TestHelmRelease_ValidateCreate(t *testing.T) {
+	t.Run("accepts a built-in strategy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := helmReleaseWithUpgradeStrategy(UninstallRemediationStrategy)
+		_, err := obj.ValidateCreate()
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("rejects an unknown strategy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := helmReleaseWithUpgradeStrategy(RemediationStrategy("bogus"))
+		_, err := obj.ValidateCreate()
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("spec.upgrade.remediation"))
+	})
+
+	t.Run("accepts a strategy registered after startup", func(t *testing.T) {
+		g := NewWithT(t)
+
+		strategy := RemediationStrategy("Canary")
+		g.Expect(IsValidRemediationStrategy(strategy)).To(BeFalse())
+		RegisterRemediationStrategy(strategy)
+
+		obj := helmReleaseWithUpgradeStrategy(strategy)
+		_, err := obj.ValidateCreate()
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+
+	t.Run("accepts no remediation configured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := (&HelmRelease{}).ValidateCreate()
+		g.Expect(err).ToNot(HaveOccurred())
+	})
+}
+
+func TestHelmRelease_ValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := helmReleaseWithUpgradeStrategy(RemediationStrategy("bogus"))
+	_, err := obj.ValidateUpdate(&HelmRelease{})
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import "fmt"
+
+// Snapshot captures a point-in-time copy of the status information for a
+// Helm release as managed by the controller.
+type Snapshot struct {
+	// Name is the name of the Helm release.
+	Name string `json:"name"`
+
+	// Namespace is the namespace the Helm release is performed in.
+	Namespace string `json:"namespace"`
+
+	// Version is the version of the Helm release.
+	Version int `json:"version"`
+
+	// ChartVersion is the chart version of the Helm release.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+
+	// Status is the current state of the release.
+	Status string `json:"status"`
+
+	// TestHooks is the list of test hooks for the release as observed to be
+	// run by the controller.
+	// +optional
+	TestHooks *map[string]TestHookStatus `json:"testHooks,omitempty"`
+}
+
+// FullReleaseName returns the full name of the release, including the
+// namespace.
+func (in Snapshot) FullReleaseName() string {
+	return fmt.Sprintf("%s/%s.v%d", in.Namespace, in.Name, in.Version)
+}
+
+// TestHookStatus holds the status information for a test hook as observed
+// to be run by the controller.
+type TestHookStatus struct {
+	// LastStarted is the time the test hook was last started.
+	// +optional
+	LastStarted string `json:"lastStarted,omitempty"`
+
+	// LastCompleted is the time the test hook last completed.
+	// +optional
+	LastCompleted string `json:"lastCompleted,omitempty"`
+
+	// Phase the test hook was observed to be in.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+}
+
+// Snapshots holds the ordered history of Helm releases performed for a
+// HelmRelease, with the latest release at index 0.
+type Snapshots []*Snapshot
+
+// Latest returns the most recent Snapshot in the history, or nil if there is
+// none.
+func (in Snapshots) Latest() *Snapshot {
+	if len(in) == 0 {
+		return nil
+	}
+	return in[0]
+}
+
+// Previous returns the Snapshot preceding the latest one. If
+// ignoreTestFailures is true, any Snapshot with Status "test failed" is
+// skipped over.
+func (in Snapshots) Previous(ignoreTestFailures bool) *Snapshot {
+	if len(in) < 2 {
+		return nil
+	}
+	for _, s := range in[1:] {
+		if ignoreTestFailures && s.Status == "test failed" {
+			continue
+		}
+		return s
+	}
+	return nil
+}
+
+// Truncate removes all Snapshots older than the one returned by
+// Previous(ignoreTestFailures), keeping the latest Snapshot and the previous
+// one. If ignoreTestFailures is true, Snapshots with Status "test failed"
+// between the two are kept as well.
+func (in *Snapshots) Truncate(ignoreTestFailures bool) {
+	if in == nil || len(*in) == 0 {
+		return
+	}
+	kept := Snapshots{(*in)[0]}
+	for _, s := range (*in)[1:] {
+		kept = append(kept, s)
+		if ignoreTestFailures && s.Status == "test failed" {
+			continue
+		}
+		break
+	}
+	*in = kept
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import "sync"
+
+// remediationStrategies holds the set of RemediationStrategy names accepted
+// by the validating webhook, in addition to RollbackRemediationStrategy and
+// UninstallRemediationStrategy. A running controller registers any
+// additional strategy it supports (e.g. via reconcile.RegisterRemediationStrategy)
+// through RegisterRemediationStrategy, so that the webhook accepts exactly
+// the set of strategies the controller can actually act on.
+var remediationStrategies = struct {
+	sync.RWMutex
+	m map[RemediationStrategy]struct{}
+}{
+	m: map[RemediationStrategy]struct{}{
+		RollbackRemediationStrategy:  {},
+		UninstallRemediationStrategy: {},
+	},
+}
+
+// RegisterRemediationStrategy adds name to the set of RemediationStrategy
+// values accepted by the validating webhook.
+func RegisterRemediationStrategy(name RemediationStrategy) {
+	remediationStrategies.Lock()
+	defer remediationStrategies.Unlock()
+	remediationStrategies.m[name] = struct{}{}
+}
+
+// IsValidRemediationStrategy returns true if name is RollbackRemediationStrategy,
+// UninstallRemediationStrategy, or was previously registered through
+// RegisterRemediationStrategy.
+func IsValidRemediationStrategy(name RemediationStrategy) bool {
+	remediationStrategies.RLock()
+	defer remediationStrategies.RUnlock()
+	_, ok := remediationStrategies.m[name]
+	return ok
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+// RemediationStrategy is the name of a strategy for remediating a failed
+// Helm release, applied between retries and, if RemediateLastFailure is
+// set, after the last failure.
+//
+// It is an open string type rather than a closed enum: in addition to the
+// built-in RollbackRemediationStrategy and UninstallRemediationStrategy, a
+// controller may be configured with additional RemediationStrategy
+// implementations (e.g. reconcile.WithRemediationStrategy), so the API
+// cannot enumerate the full set of valid values at compile time. Validation
+// of the configured value against the strategies actually registered with
+// the running controller is performed by the validating webhook at admission
+// time, rather than through a CRD enum.
+type RemediationStrategy string
+
+const (
+	// RollbackRemediationStrategy represents a remediation strategy where
+	// the release is rolled back to the previous release.
+	RollbackRemediationStrategy RemediationStrategy = "rollback"
+
+	// UninstallRemediationStrategy represents a remediation strategy where
+	// the release is uninstalled.
+	UninstallRemediationStrategy RemediationStrategy = "uninstall"
+)
+
+// Remediation defines the strategy to use, and configuration for, remediation
+// actions taken when the performed Helm action of a release fails.
+type Remediation struct {
+	// Retries is the number of retries that should be attempted on failures
+	// before bailing. Remediation, using Strategy, is performed between
+	// each retry. Defaults to '0', a negative integer equals to unlimited
+	// retries.
+	// +optional
+	Retries int `json:"retries,omitempty"`
+
+	// IgnoreTestFailures tells the controller to skip remediation when the
+	// failure is a test failure.
+	// +optional
+	IgnoreTestFailures *bool `json:"ignoreTestFailures,omitempty"`
+
+	// RemediateLastFailure tells the controller to remediate the last
+	// failure, when no retries remain. Defaults to 'false' unless Retries is
+	// greater than 0.
+	// +optional
+	RemediateLastFailure *bool `json:"remediateLastFailure,omitempty"`
+
+	// Strategy to use for failure remediation. Defaults to
+	// RollbackRemediationStrategy.
+	// +optional
+	Strategy *RemediationStrategy `json:"strategy,omitempty"`
+
+	// The failure counts that Retries is compared against are not held
+	// here; they are tracked on HelmReleaseStatus (see GetFailureCount).
+}
+
+// GetStrategy returns the configured RemediationStrategy, or
+// RollbackRemediationStrategy if unset.
+func (in Remediation) GetStrategy() RemediationStrategy {
+	if in.Strategy == nil {
+		return RollbackRemediationStrategy
+	}
+	return *in.Strategy
+}
+
+// MustIgnoreTestFailures returns whether the Remediation strategy specifies
+// to ignore test failures, or the given default if unset.
+func (in Remediation) MustIgnoreTestFailures(def bool) bool {
+	if in.IgnoreTestFailures == nil {
+		return def
+	}
+	return *in.IgnoreTestFailures
+}
+
+// MustRemediateLastFailure returns whether to remediate the last failure,
+// when no retries remain.
+func (in Remediation) MustRemediateLastFailure() bool {
+	if in.RemediateLastFailure == nil {
+		return in.Retries > 0
+	}
+	return *in.RemediateLastFailure
+}
+
+// RetriesExhausted returns whether the configured number of retries has been
+// exhausted, based on the failure count recorded for obj.
+func (in Remediation) RetriesExhausted(obj *HelmRelease) bool {
+	if in.Retries < 0 {
+		return false
+	}
+	return in.GetFailureCount(obj) > int64(in.Retries)
+}
+
+// GetFailureCount returns the failure count recorded for the active release
+// action on obj.
+func (in Remediation) GetFailureCount(obj *HelmRelease) int64 {
+	switch obj.Status.LastAttemptedReleaseAction {
+	case "install":
+		return obj.Status.InstallFailures
+	case "upgrade":
+		return obj.Status.UpgradeFailures
+	default:
+		return 0
+	}
+}
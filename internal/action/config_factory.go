@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"time"
+
+	helmaction "helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/storage"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ConfigFactory builds Helm action.Configuration instances for a release
+// target, so that individual ActionReconcilers do not need to know how to
+// wire up the Helm SDK themselves.
+type ConfigFactory struct {
+	// RESTClientGetter is used to build the Helm action.Configuration, and
+	// is also exposed directly for reconcilers (such as DriftCorrect) that
+	// need to talk to the cluster without going through Helm.
+	RESTClientGetter genericclioptions.RESTClientGetter
+
+	// Releases is the Helm storage driver to use for the configured release
+	// target.
+	Releases *storage.Storage
+
+	// DefaultPendingReleaseTimeout is the amount of time a release is
+	// allowed to remain in a pending-install, pending-upgrade or
+	// pending-rollback Helm status before it is considered stuck, used as
+	// the fallback when a HelmRelease does not set
+	// spec.pendingReleaseTimeout. Defaults to 5 minutes when zero.
+	DefaultPendingReleaseTimeout time.Duration
+}
+
+// Build returns a Helm action.Configuration for the release target
+// configured on the ConfigFactory.
+func (cf *ConfigFactory) Build(observeLog func(format string, v ...interface{})) *helmaction.Configuration {
+	if observeLog == nil {
+		observeLog = func(string, ...interface{}) {}
+	}
+	return &helmaction.Configuration{
+		RESTClientGetter: cf.RESTClientGetter,
+		Releases:         cf.Releases,
+		Log:              observeLog,
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"fmt"
+
+	helmrelease "helm.sh/helm/v3/pkg/release"
+)
+
+// MarkFailed marks rls as release.StatusFailed for the given reason, and
+// persists the result directly through the storage driver of the Helm
+// action configuration. Unlike Rollback, it does not attempt to restore the
+// cluster to any previous state; it merely updates the recorded status of
+// the release so that it is no longer considered "pending" by Helm.
+//
+// This mirrors the approach taken by helm-operator-plugins for recovering
+// from a release that has been pending for longer than is reasonable to
+// assume it is still in progress.
+func MarkFailed(cfg *ConfigFactory, rls *helmrelease.Release, reason string) (*helmrelease.Release, error) {
+	if rls == nil {
+		return nil, fmt.Errorf("cannot mark failed: no release provided")
+	}
+
+	// Info is a pointer, and SetStatus mutates it in place, so it must be
+	// copied separately to avoid aliasing the caller's original release.
+	releaseCopy := *rls
+	if rls.Info != nil {
+		infoCopy := *rls.Info
+		releaseCopy.Info = &infoCopy
+	}
+	releaseCopy.SetStatus(helmrelease.StatusFailed, reason)
+
+	cfg2 := cfg.Build(nil)
+	if err := cfg2.Releases.Update(&releaseCopy); err != nil {
+		return nil, fmt.Errorf("unable to persist release %s/%d as failed: %w", releaseCopy.Name, releaseCopy.Version, err)
+	}
+
+	return &releaseCopy, nil
+}
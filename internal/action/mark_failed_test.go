@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package action
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestMarkFailed(t *testing.T) {
+	t.Run("does not mutate the caller's release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		rls := &helmrelease.Release{
+			Name:    "release",
+			Version: 1,
+			Info: &helmrelease.Info{
+				Status: helmrelease.StatusPendingUpgrade,
+			},
+		}
+
+		store := storage.Init(driver.NewMemory())
+		g.Expect(store.Create(rls)).To(Succeed())
+
+		cfg := &ConfigFactory{Releases: store}
+
+		result, err := MarkFailed(cfg, rls, "timed out")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(result.Info.Status).To(Equal(helmrelease.StatusFailed))
+		g.Expect(rls.Info.Status).To(Equal(helmrelease.StatusPendingUpgrade),
+			"original release must not be mutated by MarkFailed")
+	})
+
+	t.Run("errors without a release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := MarkFailed(&ConfigFactory{}, nil, "timed out")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -36,7 +37,6 @@ import (
 	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
 	"github.com/fluxcd/helm-controller/internal/action"
 	"github.com/fluxcd/helm-controller/internal/diff"
-	interrors "github.com/fluxcd/helm-controller/internal/errors"
 )
 
 // OwnedConditions is a list of Condition types owned by the HelmRelease object.
@@ -67,6 +67,13 @@ var (
 	ErrUnknownRemediationStrategy = errors.New("unknown remediation strategy")
 )
 
+// reasonLastReleaseNotDeployed is the ReleaseState.Reason set by
+// DetermineReleaseState when the latest entry in the release history has a
+// Helm status other than release.StatusDeployed, release.StatusFailed or a
+// pending status (e.g. Superseded), which is treated as
+// ReleaseStatusOutOfSync so that a Force upgrade can run to recover it.
+const reasonLastReleaseNotDeployed = "LastReleaseNotDeployed"
+
 // AtomicRelease is an ActionReconciler which implements an atomic release
 // strategy similar to Helm's `--atomic`, but with more advanced state
 // determination. It determines the next action to take based on the current
@@ -106,18 +113,31 @@ type AtomicRelease struct {
 	eventRecorder record.EventRecorder
 	strategy      releaseStrategy
 	fieldManager  string
+
+	// strategies holds the built-in rollback and uninstall
+	// RemediationStrategy implementations, and any registered through
+	// WithRemediationStrategy, keyed by Name().
+	strategies map[string]RemediationStrategy
 }
 
 // NewAtomicRelease returns a new AtomicRelease reconciler configured with the
 // provided values.
-func NewAtomicRelease(patchHelper *patch.SerialPatcher, cfg *action.ConfigFactory, recorder record.EventRecorder, fieldManager string) *AtomicRelease {
-	return &AtomicRelease{
+func NewAtomicRelease(patchHelper *patch.SerialPatcher, cfg *action.ConfigFactory, recorder record.EventRecorder, fieldManager string, opts ...AtomicReleaseOption) *AtomicRelease {
+	r := &AtomicRelease{
 		patchHelper:   patchHelper,
 		eventRecorder: recorder,
 		configFactory: cfg,
 		strategy:      &cleanReleaseStrategy{},
 		fieldManager:  fieldManager,
+		strategies: map[string]RemediationStrategy{
+			string(v2.RollbackRemediationStrategy):  &rollbackStrategy{configFactory: cfg, eventRecorder: recorder},
+			string(v2.UninstallRemediationStrategy): &uninstallStrategy{configFactory: cfg, eventRecorder: recorder},
+		},
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 // releaseStrategy defines the continue-stop behavior of the reconcile loop.
@@ -141,10 +161,11 @@ func (cleanReleaseStrategy) MustContinue(current ReconcilerType, previous Reconc
 	return !previous.Contains(current)
 }
 
-// MustStop returns true if current equals ReconcilerTypeRemediate.
+// MustStop returns true if current equals ReconcilerTypeRemediate or
+// ReconcilerTypePendingFailed.
 func (cleanReleaseStrategy) MustStop(current ReconcilerType, _ ReconcilerTypeSet) bool {
 	switch current {
-	case ReconcilerTypeRemediate:
+	case ReconcilerTypeRemediate, ReconcilerTypePendingFailed:
 		return true
 	default:
 		return false
@@ -186,8 +207,12 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 			log.V(logger.DebugLevel).Info("determining next Helm action based on current state")
 			if next, err = r.actionForState(ctx, req, state); err != nil {
 				if errors.Is(err, ErrExceededMaxRetries) {
+					var failureCount int64
+					if remediation := req.Object.GetActiveRemediation(); remediation != nil {
+						failureCount = remediation.GetFailureCount(req.Object)
+					}
 					conditions.MarkStalled(req.Object, "RetriesExceeded", "Failed to %s after %d attempt(s)",
-						req.Object.Status.LastAttemptedReleaseAction, req.Object.GetActiveRemediation().GetFailureCount(req.Object))
+						req.Object.Status.LastAttemptedReleaseAction, failureCount)
 				}
 				return err
 			}
@@ -200,7 +225,11 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 				// written to Ready.
 				summarize(req)
 
-				return nil
+				// Persist any condition changes made while determining the
+				// state or preparing the remediation strategy (e.g.
+				// PauseStrategy), as this is the only return path for a
+				// strategy whose ActionReconciler is nil.
+				return r.patchHelper.Patch(ctx, req.Object, patch.WithOwnedConditions{Conditions: OwnedConditions}, patch.WithFieldOwner(r.fieldManager))
 			}
 
 			// If we are not allowed to run the next action, we are done for now...
@@ -251,10 +280,24 @@ func (r *AtomicRelease) Reconcile(ctx context.Context, req *Request) error {
 				)
 				conditions.Delete(req.Object, meta.ReconcilingCondition)
 
+				var stopErr error
 				if remediation := req.Object.GetActiveRemediation(); remediation == nil || !remediation.RetriesExhausted(req.Object) {
-					return ErrMustRequeue
+					stopErr = ErrMustRequeue
 				}
-				return nil
+
+				// Always summarize before patching, so Ready reflects the
+				// ReleasedCondition/RemediatedCondition change just made by
+				// the action we ran (e.g. MarkFailed), the same as the
+				// next == nil branch above.
+				summarize(req)
+
+				// Persist the conditions set by the action we just ran (e.g.
+				// MarkFailed's ReleasedCondition) before returning, for the
+				// same reason as the next == nil branch above.
+				if err = r.patchHelper.Patch(ctx, req.Object, patch.WithOwnedConditions{Conditions: OwnedConditions}, patch.WithFieldOwner(r.fieldManager)); err != nil {
+					return err
+				}
+				return stopErr
 			}
 
 			// Append the type to the set of action types we have performed.
@@ -293,7 +336,28 @@ func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state
 		return nil, nil
 	case ReleaseStatusLocked:
 		log.Info(msgWithReason("release locked", state.Reason))
-		return NewUnlock(r.configFactory, r.eventRecorder), nil
+
+		// A release may legitimately remain in a pending-install,
+		// pending-upgrade or pending-rollback state for a while, e.g. due to
+		// long-running hooks or slow resource readiness. Only treat it as
+		// stuck once it has been pending for longer than the configured
+		// grace period.
+		timeout := req.Object.GetPendingReleaseTimeout(metav1.Duration{Duration: r.configFactory.DefaultPendingReleaseTimeout}).Duration
+		if state.Release == nil || state.Release.Info == nil || state.Release.Info.LastDeployed.IsZero() {
+			return NewUnlock(r.configFactory, r.eventRecorder), nil
+		}
+		if elapsed := time.Since(state.Release.Info.LastDeployed.Time); elapsed < timeout {
+			log.Info(fmt.Sprintf("release has been pending for %s, waiting for pendingReleaseTimeout of %s before acting",
+				elapsed.Round(time.Second), timeout))
+			return nil, ErrMustRequeue
+		}
+
+		log.Info(fmt.Sprintf("release has been pending for longer than the configured pendingReleaseTimeout of %s", timeout))
+
+		// MarkFailed.Reconcile owns emitting the event and marking
+		// ReleasedCondition false for this transition; doing it here too
+		// would record it twice.
+		return NewMarkFailed(r.configFactory, r.eventRecorder), nil
 	case ReleaseStatusAbsent:
 		log.Info(msgWithReason("release not installed", state.Reason))
 
@@ -316,6 +380,15 @@ func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state
 			return nil, fmt.Errorf("%w: cannot upgrade release", ErrExceededMaxRetries)
 		}
 
+		// If the latest release in storage did not end up Deployed (e.g. it
+		// is Superseded), a regular upgrade may have no changes to
+		// apply and would be reported as in-sync without actually fixing
+		// anything. Force the upgrade in that case, so Helm overwrites the
+		// broken state left behind by the previous attempt.
+		if state.Reason == reasonLastReleaseNotDeployed {
+			return NewUpgrade(r.configFactory, r.eventRecorder, WithForce(true)), nil
+		}
+
 		return NewUpgrade(r.configFactory, r.eventRecorder), nil
 	case ReleaseStatusDrifted:
 		log.Info(msgWithReason("detected changes in cluster state", diff.SummarizeDiffSetBrief(state.Diff)))
@@ -330,7 +403,10 @@ func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state
 			req.Object.Status.History.Latest().FullReleaseName(), diff.SummarizeDiffSet(state.Diff),
 		)
 
-		if req.Object.GetDriftDetection().GetMode() == v2.DriftDetectionEnabled {
+		switch req.Object.GetDriftDetection().GetMode() {
+		case v2.DriftCorrectionModeSSA:
+			return NewDriftCorrect(r.configFactory, r.eventRecorder, r.fieldManager, state.Diff), nil
+		case v2.DriftDetectionEnabled:
 			return NewUpgrade(r.configFactory, r.eventRecorder), nil
 		}
 
@@ -370,29 +446,14 @@ func (r *AtomicRelease) actionForState(ctx context.Context, req *Request, state
 		// This ensures we do not accumulate a long history of failures.
 		req.Object.Status.History.Truncate(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
 
-		switch remediation.GetStrategy() {
-		case v2.RollbackRemediationStrategy:
-			// Verify the previous release is still in storage and unmodified
-			// before instructing to roll back to it.
-			prev := req.Object.Status.History.Previous(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
-			if _, err := action.VerifySnapshot(r.configFactory.Build(nil), prev); err != nil {
-				if interrors.IsOneOf(err, action.ErrReleaseNotFound, action.ErrReleaseDisappeared, action.ErrReleaseNotObserved, action.ErrReleaseDigest) {
-					// If the rollback target is not found or is in any other
-					// way corrupt, the most correct remediation is to
-					// reattempt the upgrade.
-					log.Info(msgWithReason("unable to verify previous release in storage to roll back to", err.Error()))
-					return NewUpgrade(r.configFactory, r.eventRecorder), nil
-				}
-
-				// This may be a temporary error, return it to retry.
-				return nil, fmt.Errorf("cannot verify previous release to roll back to: %w", err)
-			}
-			return NewRollbackRemediation(r.configFactory, r.eventRecorder), nil
-		case v2.UninstallRemediationStrategy:
-			return NewUninstallRemediation(r.configFactory, r.eventRecorder), nil
-		default:
+		strategy, ok := r.strategies[string(remediation.GetStrategy())]
+		if !ok {
 			return nil, fmt.Errorf("%w: %s", ErrUnknownRemediationStrategy, remediation.GetStrategy())
 		}
+		if err := strategy.Prepare(ctx, req); err != nil {
+			return nil, err
+		}
+		return strategy.ActionReconciler(), nil
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnknownReleaseStatus, state.Status)
 	}
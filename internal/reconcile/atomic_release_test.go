@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/patch"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+// TestAtomicRelease_Reconcile_PauseStrategyPersists confirms that when a
+// RemediationStrategy's ActionReconciler is nil (the next == nil branch),
+// any condition changes made by the strategy's Prepare step - such as
+// PauseStrategy marking the object Stalled - are actually patched back
+// through the patchHelper before Reconcile returns, rather than only being
+// held in the in-memory Request.Object.
+func TestAtomicRelease_Reconcile_PauseStrategyPersists(t *testing.T) {
+	g := NewWithT(t)
+
+	trueVal := true
+	pauseStrategy := v2.RemediationStrategy("Pause")
+	obj := &v2.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "pause-release", Namespace: "default"},
+		Spec: v2.HelmReleaseSpec{
+			Upgrade: &v2.Upgrade{
+				Remediation: &v2.UpgradeRemediation{
+					Strategy:             &pauseStrategy,
+					Retries:              0,
+					RemediateLastFailure: &trueVal,
+				},
+			},
+		},
+		Status: v2.HelmReleaseStatus{
+			LastAttemptedReleaseAction: "upgrade",
+			UpgradeFailures:            1,
+			History: v2.Snapshots{
+				{Name: "release", Namespace: "default", Version: 1, Status: string(helmrelease.StatusFailed)},
+			},
+		},
+	}
+
+	rls := &helmrelease.Release{
+		Name:    "release",
+		Version: 1,
+		Info: &helmrelease.Info{
+			Status:       helmrelease.StatusFailed,
+			LastDeployed: helmtime.Time{Time: time.Now()},
+		},
+	}
+	store := storage.Init(driver.NewMemory())
+	g.Expect(store.Create(rls)).To(Succeed())
+	cfg := &action.ConfigFactory{Releases: store}
+
+	scheme := runtime.NewScheme()
+	g.Expect(v2.AddToScheme(scheme)).To(Succeed())
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&v2.HelmRelease{}).
+		WithObjects(obj).
+		Build()
+
+	patchHelper := patch.NewSerialPatcher(obj, client)
+	recorder := record.NewFakeRecorder(32)
+
+	r := NewAtomicRelease(patchHelper, cfg, recorder, "test-controller", WithRemediationStrategy(NewPauseStrategy(recorder)))
+
+	err := r.Reconcile(context.Background(), &Request{Object: obj})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	persisted := &v2.HelmRelease{}
+	g.Expect(client.Get(context.Background(), types.NamespacedName{Name: obj.Name, Namespace: obj.Namespace}, persisted)).To(Succeed())
+
+	g.Expect(persisted.Spec.Suspend).To(BeFalse(), "PauseStrategy must not mutate spec.suspend")
+	g.Expect(conditions.IsStalled(persisted)).To(BeTrue(), "Stalled condition set by PauseStrategy must be persisted")
+}
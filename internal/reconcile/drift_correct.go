@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/logger"
+	"github.com/fluxcd/pkg/ssa"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+	"github.com/fluxcd/helm-controller/internal/diff"
+)
+
+// ReconcilerTypeDriftCorrect is the ReconcilerType for a DriftCorrect action
+// reconciler.
+const ReconcilerTypeDriftCorrect ReconcilerType = "drift-correct"
+
+// resourceApplier is the subset of *ssa.ResourceManager's behavior DriftCorrect
+// depends on. It exists so tests can substitute a stub for the real
+// server-side apply client, which otherwise requires a live API server.
+type resourceApplier interface {
+	ApplyAll(ctx context.Context, objects []*unstructured.Unstructured, opts ssa.ApplyOptions) (*ssa.ChangeSet, error)
+}
+
+// DriftCorrect is an ActionReconciler which corrects observed cluster-side
+// drift by server-side applying only the objects reported as created or
+// updated in the provided jsondiff.DiffSet, without invoking Helm at all.
+// Each such object is applied with its full desired state, not only the
+// fields that drifted. This is a much cheaper alternative to a full Upgrade for
+// clusters where drift is routinely (re)introduced, e.g. by mutating
+// admission webhooks.
+type DriftCorrect struct {
+	configFactory *action.ConfigFactory
+	eventRecorder record.EventRecorder
+	fieldManager  string
+
+	diff jsondiff.DiffSet
+
+	// resourceApplier, when set, is used instead of building a
+	// *ssa.ResourceManager from configFactory. Used by tests to inject a
+	// stub in place of a real cluster connection.
+	resourceApplier resourceApplier
+}
+
+// NewDriftCorrect returns a new DriftCorrect reconciler configured with the
+// provided values, correcting the drift described by diffSet.
+func NewDriftCorrect(cfg *action.ConfigFactory, recorder record.EventRecorder, fieldManager string, diffSet jsondiff.DiffSet) *DriftCorrect {
+	return &DriftCorrect{
+		configFactory: cfg,
+		eventRecorder: recorder,
+		fieldManager:  fieldManager,
+		diff:          diffSet,
+	}
+}
+
+func (r *DriftCorrect) Reconcile(ctx context.Context, req *Request) error {
+	log := ctrl.LoggerFrom(ctx).V(logger.InfoLevel)
+
+	var objects []*unstructured.Unstructured
+	var applied jsondiff.DiffSet
+	for _, change := range r.diff {
+		if change.Type != jsondiff.DiffTypeCreate && change.Type != jsondiff.DiffTypeUpdate {
+			continue
+		}
+		u, ok := change.DesiredObject.(*unstructured.Unstructured)
+		if !ok {
+			log.Info("skipping drift correction for object with unexpected desired object type",
+				"type", fmt.Sprintf("%T", change.DesiredObject))
+			continue
+		}
+		objects = append(objects, u)
+		applied = append(applied, change)
+	}
+
+	if len(objects) == 0 {
+		log.Info("no drifted objects to correct")
+		return nil
+	}
+
+	resourceManager := r.resourceApplier
+	if resourceManager == nil {
+		var err error
+		resourceManager, err = newResourceManager(r.configFactory.Build(nil).RESTClientGetter, r.fieldManager)
+		if err != nil {
+			return fmt.Errorf("failed to build resource manager to correct drift: %w", err)
+		}
+	}
+
+	changeSet, err := resourceManager.ApplyAll(ctx, objects, ssa.DefaultApplyOptions())
+	if err != nil {
+		conditions.MarkFalse(req.Object, v2.ReleasedCondition, "DriftCorrectionFailed", err.Error())
+		return fmt.Errorf("failed to correct drift: %w", err)
+	}
+
+	log.Info(fmt.Sprintf("corrected drift on %d object(s)", len(changeSet.Entries)))
+
+	r.eventRecorder.Eventf(req.Object, corev1.EventTypeNormal, "DriftCorrected",
+		"Corrected cluster state drift of release %s:\n%s",
+		req.Object.Status.History.Latest().FullReleaseName(), diff.SummarizeDiffSet(applied),
+	)
+
+	return nil
+}
+
+func (r *DriftCorrect) Name() string {
+	return "drift-correct"
+}
+
+func (r *DriftCorrect) Type() ReconcilerType {
+	return ReconcilerTypeDriftCorrect
+}
+
+// newResourceManager builds a *ssa.ResourceManager for the cluster described
+// by getter, owned by fieldManager.
+func newResourceManager(getter genericclioptions.RESTClientGetter, fieldManager string) (*ssa.ResourceManager, error) {
+	restConfig, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build REST config: %w", err)
+	}
+
+	restMapper, err := getter.ToRESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build REST mapper: %w", err)
+	}
+
+	kubeClient, err := client.New(restConfig, client.Options{Mapper: restMapper})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kube client: %w", err)
+	}
+
+	statusPoller := polling.NewStatusPoller(kubeClient, restMapper, polling.Options{})
+
+	return ssa.NewResourceManager(kubeClient, statusPoller, ssa.Owner{
+		Field: fieldManager,
+	}), nil
+}
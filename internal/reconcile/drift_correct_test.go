@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/ssa"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+)
+
+// stubResourceApplier is a resourceApplier which returns a canned result
+// instead of talking to a real cluster, recording the objects it was asked
+// to apply.
+type stubResourceApplier struct {
+	changeSet *ssa.ChangeSet
+	err       error
+
+	applied []*unstructured.Unstructured
+}
+
+func (s *stubResourceApplier) ApplyAll(_ context.Context, objects []*unstructured.Unstructured, _ ssa.ApplyOptions) (*ssa.ChangeSet, error) {
+	s.applied = objects
+	return s.changeSet, s.err
+}
+
+func newDriftObject() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetName("drifted")
+	u.SetNamespace("default")
+	return u
+}
+
+func TestDriftCorrect_Reconcile(t *testing.T) {
+	t.Run("does nothing when there is no drift to correct", func(t *testing.T) {
+		g := NewWithT(t)
+
+		r := NewDriftCorrect(nil, record.NewFakeRecorder(32), "helm-controller", nil)
+		g.Expect(r.Reconcile(context.Background(), &Request{Object: &v2.HelmRelease{}})).To(Succeed())
+	})
+
+	t.Run("skips diff entries whose desired object is not unstructured", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diffSet := jsondiff.DiffSet{
+			{Type: jsondiff.DiffTypeUpdate, DesiredObject: newDriftObject()},
+			{Type: jsondiff.DiffTypeUpdate, DesiredObject: &v2.HelmRelease{}},
+		}
+		recorder := record.NewFakeRecorder(32)
+
+		r := NewDriftCorrect(nil, recorder, "helm-controller", diffSet)
+		applier := &stubResourceApplier{changeSet: &ssa.ChangeSet{}}
+		r.resourceApplier = applier
+
+		obj := &v2.HelmRelease{Status: v2.HelmReleaseStatus{
+			History: v2.Snapshots{{Name: "release", Namespace: "default", Version: 1}},
+		}}
+
+		g.Expect(r.Reconcile(context.Background(), &Request{Object: obj})).To(Succeed())
+		g.Expect(applier.applied).To(HaveLen(1), "the non-unstructured entry must not be passed to ApplyAll")
+	})
+
+	t.Run("applies drift and emits an event on success", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diffSet := jsondiff.DiffSet{{Type: jsondiff.DiffTypeUpdate, DesiredObject: newDriftObject()}}
+		recorder := record.NewFakeRecorder(32)
+
+		r := NewDriftCorrect(nil, recorder, "helm-controller", diffSet)
+		r.resourceApplier = &stubResourceApplier{changeSet: &ssa.ChangeSet{}}
+
+		obj := &v2.HelmRelease{Status: v2.HelmReleaseStatus{
+			History: v2.Snapshots{{Name: "release", Namespace: "default", Version: 1}},
+		}}
+
+		g.Expect(r.Reconcile(context.Background(), &Request{Object: obj})).To(Succeed())
+
+		select {
+		case e := <-recorder.Events:
+			g.Expect(e).To(ContainSubstring("DriftCorrected"))
+		default:
+			t.Fatal("expected a DriftCorrected event to be recorded")
+		}
+	})
+
+	t.Run("marks ReleasedCondition false when apply fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diffSet := jsondiff.DiffSet{{Type: jsondiff.DiffTypeUpdate, DesiredObject: newDriftObject()}}
+
+		r := NewDriftCorrect(nil, record.NewFakeRecorder(32), "helm-controller", diffSet)
+		r.resourceApplier = &stubResourceApplier{err: errors.New("apply denied")}
+
+		obj := &v2.HelmRelease{Status: v2.HelmReleaseStatus{
+			History: v2.Snapshots{{Name: "release", Namespace: "default", Version: 1}},
+		}}
+
+		err := r.Reconcile(context.Background(), &Request{Object: obj})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(conditions.IsFalse(obj, v2.ReleasedCondition)).To(BeTrue())
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+// ReconcilerTypePendingFailed is the ReconcilerType for a MarkFailed action
+// reconciler.
+const ReconcilerTypePendingFailed ReconcilerType = "pending-failed"
+
+// MarkFailed is an ActionReconciler which marks the latest release in the
+// Helm storage as release.StatusFailed, without performing any further Helm
+// mutations (such as a rollback). It is run once a release has been stuck in
+// a pending-install/pending-upgrade/pending-rollback state for longer than
+// the configured spec.pendingReleaseTimeout, so that the release enters the
+// ReleaseStatusFailed path and the configured remediation strategy can take
+// over.
+type MarkFailed struct {
+	configFactory *action.ConfigFactory
+	eventRecorder record.EventRecorder
+}
+
+// NewMarkFailed returns a new MarkFailed reconciler configured with the
+// provided values.
+func NewMarkFailed(cfg *action.ConfigFactory, recorder record.EventRecorder) *MarkFailed {
+	return &MarkFailed{
+		configFactory: cfg,
+		eventRecorder: recorder,
+	}
+}
+
+func (r *MarkFailed) Reconcile(ctx context.Context, req *Request) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	latest := req.Object.Status.History.Latest()
+	if latest == nil {
+		return fmt.Errorf("could not mark release as failed: no release found in storage")
+	}
+
+	rls, err := action.VerifySnapshot(r.configFactory.Build(nil), latest)
+	if err != nil {
+		return fmt.Errorf("could not mark release as failed: %w", err)
+	}
+
+	timeout := req.Object.GetPendingReleaseTimeout(metav1.Duration{Duration: r.configFactory.DefaultPendingReleaseTimeout})
+	reason := fmt.Sprintf("exceeded pendingReleaseTimeout of %s", timeout)
+	if _, err = action.MarkFailed(r.configFactory, rls, reason); err != nil {
+		return err
+	}
+
+	log.Info("marked pending release as failed", "reason", reason)
+
+	r.eventRecorder.Eventf(req.Object, corev1.EventTypeWarning, v2.PendingReleaseTimeoutReason, reason)
+	conditions.MarkFalse(req.Object, v2.ReleasedCondition, v2.PendingReleaseTimeoutReason, reason)
+
+	return nil
+}
+
+func (r *MarkFailed) Name() string {
+	return "mark-failed"
+}
+
+func (r *MarkFailed) Type() ReconcilerType {
+	return ReconcilerTypePendingFailed
+}
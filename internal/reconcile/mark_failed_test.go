@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+)
+
+func TestMarkFailed_Reconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	req, cfg := newPendingRequest(t, helmrelease.StatusPendingUpgrade, time.Now().Add(-time.Hour), &v2.HelmRelease{})
+	recorder := record.NewFakeRecorder(32)
+
+	r := NewMarkFailed(cfg, recorder)
+	g.Expect(r.Reconcile(context.Background(), req)).To(Succeed())
+
+	g.Expect(conditions.IsFalse(req.Object, v2.ReleasedCondition)).To(BeTrue())
+
+	rls, err := cfg.Releases.Get("release", 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rls.Info.Status).To(Equal(helmrelease.StatusFailed))
+
+	select {
+	case e := <-recorder.Events:
+		g.Expect(e).To(ContainSubstring(v2.PendingReleaseTimeoutReason))
+	default:
+		t.Fatal("expected a PendingReleaseTimeoutReason event to be recorded")
+	}
+}
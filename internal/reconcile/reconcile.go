@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+
+	"helm.sh/helm/v3/pkg/chart"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+)
+
+// Request is the context passed between the AtomicRelease reconciler and
+// the individual ActionReconcilers it calls into, carrying the object
+// under reconciliation as well as the (rendered) chart and values to use
+// for any Helm action.
+type Request struct {
+	// Object is the HelmRelease being reconciled.
+	Object *v2.HelmRelease
+	// Chart is the Helm chart to use for any install/upgrade/test action.
+	Chart *chart.Chart
+	// Values are the computed values to use for any install/upgrade/test
+	// action.
+	Values map[string]interface{}
+}
+
+// ReconcilerType is the type of an ActionReconciler, used by releaseStrategy
+// implementations to determine continue/stop behavior across actions run
+// within a single AtomicRelease.Reconcile call.
+type ReconcilerType string
+
+const (
+	// ReconcilerTypeRelease is the ReconcilerType for any action which
+	// installs or upgrades a release (Install, Upgrade, Test).
+	ReconcilerTypeRelease ReconcilerType = "release"
+	// ReconcilerTypeRemediate is the ReconcilerType for any action which
+	// remediates a failed release (RollbackRemediation,
+	// UninstallRemediation).
+	ReconcilerTypeRemediate ReconcilerType = "remediate"
+	// ReconcilerTypeUnlock is the ReconcilerType for the Unlock action.
+	ReconcilerTypeUnlock ReconcilerType = "unlock"
+)
+
+// ReconcilerTypeSet is a set of ReconcilerType, recording which actions have
+// already been run during a single AtomicRelease.Reconcile call.
+type ReconcilerTypeSet []ReconcilerType
+
+// Contains returns true if t is present in s.
+func (s ReconcilerTypeSet) Contains(t ReconcilerType) bool {
+	for _, v := range s {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionReconciler performs a single Helm action (or a related step, such as
+// unlocking a pending release) as part of an AtomicRelease.
+type ActionReconciler interface {
+	// Reconcile performs the action.
+	Reconcile(ctx context.Context, req *Request) error
+	// Name returns a human-readable name for the action, used in log
+	// messages and status conditions.
+	Name() string
+	// Type returns the ReconcilerType of the action.
+	Type() ReconcilerType
+}
+
+// summarize composes the Ready condition of req.Object out of the
+// individual conditions owned by AtomicRelease and its ActionReconcilers.
+func summarize(req *Request) {
+	conditions.SetSummary(req.Object, meta.ReadyCondition, conditions.WithConditions(OwnedConditions...))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+
+	helmrelease "helm.sh/helm/v3/pkg/release"
+
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+	"github.com/fluxcd/helm-controller/internal/diff"
+	interrors "github.com/fluxcd/helm-controller/internal/errors"
+)
+
+// ReleaseStatus describes the state of the Helm release associated with a
+// HelmRelease, in relation to its desired state, as determined by
+// DetermineReleaseState.
+type ReleaseStatus string
+
+const (
+	// ReleaseStatusInSync indicates the release matches the desired state,
+	// and the cluster state matches the release manifest.
+	ReleaseStatusInSync ReleaseStatus = "InSync"
+	// ReleaseStatusLocked indicates the release is pending-install,
+	// pending-upgrade or pending-rollback in the Helm storage.
+	ReleaseStatusLocked ReleaseStatus = "Locked"
+	// ReleaseStatusAbsent indicates there is no release in the Helm storage
+	// matching the HelmRelease's history.
+	ReleaseStatusAbsent ReleaseStatus = "Absent"
+	// ReleaseStatusUnmanaged indicates the release in storage can no longer
+	// be confirmed to be under the control of this HelmRelease.
+	ReleaseStatusUnmanaged ReleaseStatus = "Unmanaged"
+	// ReleaseStatusOutOfSync indicates the release no longer matches the
+	// desired state of the HelmRelease.
+	ReleaseStatusOutOfSync ReleaseStatus = "OutOfSync"
+	// ReleaseStatusDrifted indicates the cluster state has drifted from the
+	// release manifest in storage.
+	ReleaseStatusDrifted ReleaseStatus = "Drifted"
+	// ReleaseStatusUntested indicates the release has not yet been tested.
+	ReleaseStatusUntested ReleaseStatus = "Untested"
+	// ReleaseStatusFailed indicates the release is in a failed state.
+	ReleaseStatusFailed ReleaseStatus = "Failed"
+)
+
+// ReleaseState is the result of DetermineReleaseState, consumed by
+// AtomicRelease.actionForState to determine the next action to run.
+type ReleaseState struct {
+	// Status is the determined ReleaseStatus.
+	Status ReleaseStatus
+	// Reason is a human-readable (and in some cases, such as
+	// reasonLastReleaseNotDeployed, machine-matchable) explanation for
+	// Status.
+	Reason string
+	// Diff holds the set of differences between the release manifest and
+	// the objects observed on the cluster. Only populated for
+	// ReleaseStatusDrifted.
+	Diff jsondiff.DiffSet
+	// Release is the Helm release object found in the storage driver
+	// matching the latest entry in the HelmRelease's history, if any. Only
+	// populated for states which require the caller to inspect the release
+	// object directly, such as ReleaseStatusLocked.
+	Release *helmrelease.Release
+}
+
+// pendingStatuses is the set of Helm release statuses which indicate a
+// release action is still in progress.
+var pendingStatuses = map[helmrelease.Status]struct{}{
+	helmrelease.StatusPendingInstall:  {},
+	helmrelease.StatusPendingUpgrade:  {},
+	helmrelease.StatusPendingRollback: {},
+}
+
+// DetermineReleaseState determines the current ReleaseState of the Helm
+// release associated with req.Object, by inspecting the Helm storage driver
+// and, where relevant, the live cluster state.
+func DetermineReleaseState(ctx context.Context, cfg *action.ConfigFactory, req *Request) (ReleaseState, error) {
+	latest := req.Object.Status.History.Latest()
+	if latest == nil {
+		return ReleaseState{Status: ReleaseStatusAbsent, Reason: "no release in history"}, nil
+	}
+
+	rls, err := action.VerifySnapshot(cfg.Build(nil), latest)
+	if err != nil {
+		switch {
+		case interrors.IsOneOf(err, action.ErrReleaseNotFound, action.ErrReleaseDisappeared):
+			return ReleaseState{Status: ReleaseStatusAbsent, Reason: err.Error()}, nil
+		case interrors.IsOneOf(err, action.ErrReleaseNotObserved, action.ErrReleaseDigest):
+			return ReleaseState{Status: ReleaseStatusUnmanaged, Reason: err.Error()}, nil
+		default:
+			return ReleaseState{}, err
+		}
+	}
+
+	if rls.Info != nil {
+		if _, ok := pendingStatuses[rls.Info.Status]; ok {
+			return ReleaseState{Status: ReleaseStatusLocked, Reason: string(rls.Info.Status), Release: rls}, nil
+		}
+
+		if rls.Info.Status == helmrelease.StatusFailed {
+			return ReleaseState{Status: ReleaseStatusFailed, Reason: "release is in a failed state", Release: rls}, nil
+		}
+
+		if rls.Info.Status != helmrelease.StatusDeployed {
+			// The latest release in storage is not Deployed (e.g. Superseded,
+			// left behind by an interrupted upgrade). Treat it as out-of-sync
+			// so actionForState can force an upgrade to recover it.
+			return ReleaseState{Status: ReleaseStatusOutOfSync, Reason: reasonLastReleaseNotDeployed, Release: rls}, nil
+		}
+	}
+
+	if req.Object.GetDriftDetection().GetMode() != v2.DriftDetectionDisabled {
+		diffSet, err := diff.Diff(ctx, cfg, rls)
+		if err != nil {
+			return ReleaseState{}, err
+		}
+		if len(diffSet) > 0 {
+			return ReleaseState{Status: ReleaseStatusDrifted, Diff: diffSet}, nil
+		}
+	}
+
+	return ReleaseState{Status: ReleaseStatusInSync}, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	helmtime "helm.sh/helm/v3/pkg/time"
+
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+func newPendingRequest(t *testing.T, status helmrelease.Status, lastDeployed time.Time, timeout *v2.HelmRelease) (*Request, *action.ConfigFactory) {
+	t.Helper()
+
+	rls := &helmrelease.Release{
+		Name:    "release",
+		Version: 1,
+		Info: &helmrelease.Info{
+			Status:       status,
+			LastDeployed: helmtime.Time{Time: lastDeployed},
+		},
+	}
+
+	store := storage.Init(driver.NewMemory())
+	if err := store.Create(rls); err != nil {
+		t.Fatalf("failed to seed release: %v", err)
+	}
+
+	obj := timeout
+	obj.Status.History = v2.Snapshots{
+		{Name: rls.Name, Namespace: "default", Version: rls.Version, Status: string(status)},
+	}
+
+	return &Request{Object: obj}, &action.ConfigFactory{Releases: store}
+}
+
+func TestActionForState_Locked(t *testing.T) {
+	t.Run("requeues while within pendingReleaseTimeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		req, cfg := newPendingRequest(t, helmrelease.StatusPendingUpgrade, time.Now().Add(-time.Second), &v2.HelmRelease{})
+		r := NewAtomicRelease(nil, cfg, record.NewFakeRecorder(32), "test-controller")
+
+		state, err := DetermineReleaseState(context.Background(), cfg, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(state.Status).To(Equal(ReleaseStatusLocked))
+		g.Expect(state.Release).ToNot(BeNil())
+
+		_, err = r.actionForState(context.Background(), req, state)
+		g.Expect(err).To(Equal(ErrMustRequeue))
+	})
+
+	t.Run("marks failed once pendingReleaseTimeout has elapsed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		shortTimeout := metav1.Duration{Duration: time.Minute}
+		timeoutObj := &v2.HelmRelease{
+			Spec: v2.HelmReleaseSpec{
+				PendingReleaseTimeout: &shortTimeout,
+			},
+		}
+		req, cfg := newPendingRequest(t, helmrelease.StatusPendingUpgrade, time.Now().Add(-time.Hour), timeoutObj)
+		r := NewAtomicRelease(nil, cfg, record.NewFakeRecorder(32), "test-controller")
+
+		state, err := DetermineReleaseState(context.Background(), cfg, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(state.Status).To(Equal(ReleaseStatusLocked))
+
+		next, err := r.actionForState(context.Background(), req, state)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(next).To(BeAssignableToTypeOf(&MarkFailed{}))
+		g.Expect(next.Type()).To(Equal(ReconcilerTypePendingFailed))
+	})
+}
+
+func TestActionForState_OutOfSync(t *testing.T) {
+	t.Run("forces an upgrade when the last release is Superseded", func(t *testing.T) {
+		g := NewWithT(t)
+
+		req, cfg := newPendingRequest(t, helmrelease.StatusSuperseded, time.Now().Add(-time.Hour), &v2.HelmRelease{})
+		r := NewAtomicRelease(nil, cfg, record.NewFakeRecorder(32), "test-controller")
+
+		state, err := DetermineReleaseState(context.Background(), cfg, req)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(state.Status).To(Equal(ReleaseStatusOutOfSync))
+		g.Expect(state.Reason).To(Equal(reasonLastReleaseNotDeployed))
+
+		next, err := r.actionForState(context.Background(), req, state)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(next).To(BeAssignableToTypeOf(&Upgrade{}))
+		g.Expect(next.(*Upgrade).force).To(BeTrue())
+	})
+}
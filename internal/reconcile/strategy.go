@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+	interrors "github.com/fluxcd/helm-controller/internal/errors"
+)
+
+// RemediationStrategy determines how AtomicRelease responds to a release
+// that ended up in ReleaseStatusFailed with a recorded failure: after each
+// failure while retries remain, and after the last failure only if
+// RemediateLastFailure is set. Implementations are looked up by Name() in the registry
+// configured on AtomicRelease via WithRemediationStrategy, matched against
+// the HelmRelease's configured remediation strategy.
+type RemediationStrategy interface {
+	// Name returns the name under which this strategy is registered, and
+	// which is matched against the HelmRelease's configured remediation
+	// strategy.
+	Name() string
+	// Prepare is called before the ActionReconciler returned by
+	// ActionReconciler is run, to give the strategy the chance to validate
+	// or mutate the Request before the action is taken.
+	Prepare(ctx context.Context, req *Request) error
+	// ActionReconciler returns the ActionReconciler to run to carry out the
+	// remediation, or nil if the strategy does not require one.
+	ActionReconciler() ActionReconciler
+}
+
+// AtomicReleaseOption can be used to configure a new AtomicRelease
+// reconciler.
+type AtomicReleaseOption func(*AtomicRelease)
+
+// WithRemediationStrategy registers an additional RemediationStrategy which
+// can be selected through the HelmReleaseSpec's remediation strategy field,
+// in addition to the built-in RollbackRemediationStrategy and
+// UninstallRemediationStrategy. As AtomicRelease is constructed anew for
+// every reconcile, this does not also make the webhook accept the
+// strategy's name; call RegisterRemediationStrategy once during controller
+// startup for that, before the webhook starts serving requests.
+func WithRemediationStrategy(strategy RemediationStrategy) AtomicReleaseOption {
+	return func(r *AtomicRelease) {
+		if r.strategies == nil {
+			r.strategies = make(map[string]RemediationStrategy)
+		}
+		r.strategies[strategy.Name()] = strategy
+	}
+}
+
+// RegisterRemediationStrategy makes the given RemediationStrategy's name a
+// valid value for spec.install.remediation.strategy and
+// spec.upgrade.remediation.strategy, so the validating webhook accepts it.
+// Controller setup code must call this once for every custom
+// RemediationStrategy passed to WithRemediationStrategy, before the webhook
+// server starts accepting requests: registering it only as a side effect of
+// the first AtomicRelease.Reconcile call would reject the strategy on any
+// HelmRelease reconciled, or admitted, before that first reconcile runs.
+func RegisterRemediationStrategy(strategy RemediationStrategy) {
+	v2.RegisterRemediationStrategy(v2.RemediationStrategy(strategy.Name()))
+}
+
+// PauseStrategy is a RemediationStrategy which marks the HelmRelease as
+// Stalled and emits an event, so that a human can investigate the failure
+// rather than having the controller keep churning through remediation
+// attempts. It does not mutate spec.suspend, as that field is owned by the
+// HelmRelease's source of truth (e.g. Git) and a controller-side write to it
+// would either be rejected or silently reverted by GitOps reconciliation.
+type PauseStrategy struct {
+	eventRecorder record.EventRecorder
+}
+
+// NewPauseStrategy returns a new PauseStrategy configured with the provided
+// event recorder.
+func NewPauseStrategy(recorder record.EventRecorder) *PauseStrategy {
+	return &PauseStrategy{eventRecorder: recorder}
+}
+
+func (s *PauseStrategy) Name() string {
+	return "Pause"
+}
+
+func (s *PauseStrategy) Prepare(_ context.Context, req *Request) error {
+	const msg = "Suspended release after remediation retries were exhausted; investigate and resume manually"
+	conditions.MarkStalled(req.Object, "Paused", msg)
+	s.eventRecorder.Eventf(req.Object, corev1.EventTypeWarning, "Paused", msg)
+	return nil
+}
+
+func (s *PauseStrategy) ActionReconciler() ActionReconciler {
+	return nil
+}
+
+// MarkFailedStrategy is a RemediationStrategy which runs the MarkFailed
+// action, so that external tooling observing the HelmRelease's conditions
+// sees the failure without the controller performing any further Helm
+// mutation, such as a rollback or uninstall.
+type MarkFailedStrategy struct {
+	configFactory *action.ConfigFactory
+	eventRecorder record.EventRecorder
+}
+
+// NewMarkFailedStrategy returns a new MarkFailedStrategy configured with the
+// provided values.
+func NewMarkFailedStrategy(cfg *action.ConfigFactory, recorder record.EventRecorder) *MarkFailedStrategy {
+	return &MarkFailedStrategy{configFactory: cfg, eventRecorder: recorder}
+}
+
+func (s *MarkFailedStrategy) Name() string {
+	return "MarkFailed"
+}
+
+func (s *MarkFailedStrategy) Prepare(_ context.Context, _ *Request) error {
+	return nil
+}
+
+func (s *MarkFailedStrategy) ActionReconciler() ActionReconciler {
+	return NewMarkFailed(s.configFactory, s.eventRecorder)
+}
+
+// rollbackStrategy is the built-in RemediationStrategy backing
+// v2.RollbackRemediationStrategy. It is registered by default by
+// NewAtomicRelease.
+type rollbackStrategy struct {
+	configFactory *action.ConfigFactory
+	eventRecorder record.EventRecorder
+
+	next ActionReconciler
+}
+
+func (s *rollbackStrategy) Name() string {
+	return string(v2.RollbackRemediationStrategy)
+}
+
+func (s *rollbackStrategy) Prepare(_ context.Context, req *Request) error {
+	remediation := req.Object.GetActiveRemediation()
+
+	// Verify the previous release is still in storage and unmodified before
+	// instructing to roll back to it.
+	prev := req.Object.Status.History.Previous(remediation.MustIgnoreTestFailures(req.Object.GetTest().IgnoreFailures))
+	if _, err := action.VerifySnapshot(s.configFactory.Build(nil), prev); err != nil {
+		if interrors.IsOneOf(err, action.ErrReleaseNotFound, action.ErrReleaseDisappeared, action.ErrReleaseNotObserved, action.ErrReleaseDigest) {
+			// If the rollback target is not found or is in any other way
+			// corrupt, the most correct remediation is to reattempt the
+			// upgrade.
+			s.next = NewUpgrade(s.configFactory, s.eventRecorder)
+			return nil
+		}
+
+		// This may be a temporary error, return it to retry.
+		return fmt.Errorf("cannot verify previous release to roll back to: %w", err)
+	}
+
+	s.next = NewRollbackRemediation(s.configFactory, s.eventRecorder)
+	return nil
+}
+
+func (s *rollbackStrategy) ActionReconciler() ActionReconciler {
+	return s.next
+}
+
+// uninstallStrategy is the built-in RemediationStrategy backing
+// v2.UninstallRemediationStrategy. It is registered by default by
+// NewAtomicRelease.
+type uninstallStrategy struct {
+	configFactory *action.ConfigFactory
+	eventRecorder record.EventRecorder
+}
+
+func (s *uninstallStrategy) Name() string {
+	return string(v2.UninstallRemediationStrategy)
+}
+
+func (s *uninstallStrategy) Prepare(_ context.Context, _ *Request) error {
+	return nil
+}
+
+func (s *uninstallStrategy) ActionReconciler() ActionReconciler {
+	return NewUninstallRemediation(s.configFactory, s.eventRecorder)
+}
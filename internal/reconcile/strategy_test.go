@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+)
+
+// fakeStrategy is a minimal RemediationStrategy used to exercise
+// WithRemediationStrategy's registration behavior.
+type fakeStrategy struct{}
+
+func (fakeStrategy) Name() string                                { return "Fake" }
+func (fakeStrategy) Prepare(_ context.Context, _ *Request) error { return nil }
+func (fakeStrategy) ActionReconciler() ActionReconciler          { return nil }
+
+func TestWithRemediationStrategy(t *testing.T) {
+	g := NewWithT(t)
+
+	r := NewAtomicRelease(nil, nil, record.NewFakeRecorder(32), "test-controller", WithRemediationStrategy(fakeStrategy{}))
+
+	g.Expect(r.strategies).To(HaveKey("Fake"))
+}
+
+func TestRegisterRemediationStrategy(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(v2.IsValidRemediationStrategy("Fake2")).To(BeFalse())
+
+	RegisterRemediationStrategy(fakeStrategy2{})
+
+	g.Expect(v2.IsValidRemediationStrategy("Fake2")).To(BeTrue())
+}
+
+// fakeStrategy2 only exists so TestRegisterRemediationStrategy does not
+// depend on whether TestWithRemediationStrategy (or any other test in this
+// package) has already registered "Fake" with the v2 registry.
+type fakeStrategy2 struct{ fakeStrategy }
+
+func (fakeStrategy2) Name() string { return "Fake2" }
+
+func TestPauseStrategy_Prepare(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := record.NewFakeRecorder(32)
+	s := NewPauseStrategy(recorder)
+
+	g.Expect(s.Name()).To(Equal("Pause"))
+
+	obj := &v2.HelmRelease{}
+	g.Expect(s.Prepare(context.Background(), &Request{Object: obj})).To(Succeed())
+
+	g.Expect(obj.Spec.Suspend).To(BeFalse(), "PauseStrategy must not mutate spec.suspend")
+	g.Expect(conditions.Has(obj, meta.StalledCondition)).To(BeTrue())
+	g.Expect(s.ActionReconciler()).To(BeNil())
+
+	select {
+	case e := <-recorder.Events:
+		g.Expect(e).To(ContainSubstring("Paused"))
+	default:
+		t.Fatal("expected a Paused event to be recorded")
+	}
+}
+
+func TestMarkFailedStrategy(t *testing.T) {
+	g := NewWithT(t)
+
+	s := NewMarkFailedStrategy(nil, record.NewFakeRecorder(32))
+
+	g.Expect(s.Name()).To(Equal("MarkFailed"))
+	g.Expect(s.Prepare(context.Background(), &Request{Object: &v2.HelmRelease{}})).To(Succeed())
+	g.Expect(s.ActionReconciler()).To(BeAssignableToTypeOf(&MarkFailed{}))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	helmaction "helm.sh/helm/v3/pkg/action"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+
+	v2 "github.com/fluxcd/helm-controller/api/v2beta2"
+	"github.com/fluxcd/helm-controller/internal/action"
+)
+
+// Upgrade is an ActionReconciler which performs a Helm upgrade for the
+// HelmRelease using the chart and values configured on the Request.
+type Upgrade struct {
+	configFactory *action.ConfigFactory
+	eventRecorder record.EventRecorder
+
+	// force instructs Helm to force resource updates through a
+	// replacement strategy. It is set by WithForce, and used to recover a
+	// release whose latest history entry did not end up Deployed (e.g. a
+	// Superseded revision left behind by an interrupted upgrade).
+	force bool
+}
+
+// UpgradeOption can be used to configure a new Upgrade reconciler.
+type UpgradeOption func(*Upgrade)
+
+// WithForce configures the Upgrade reconciler to force resource updates
+// through a replacement strategy, equivalent to Helm's `--force` flag.
+func WithForce(force bool) UpgradeOption {
+	return func(u *Upgrade) {
+		u.force = force
+	}
+}
+
+// NewUpgrade returns a new Upgrade reconciler configured with the provided
+// values.
+func NewUpgrade(cfg *action.ConfigFactory, recorder record.EventRecorder, opts ...UpgradeOption) *Upgrade {
+	u := &Upgrade{
+		configFactory: cfg,
+		eventRecorder: recorder,
+	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
+}
+
+func (r *Upgrade) Reconcile(ctx context.Context, req *Request) error {
+	upgrade := helmaction.NewUpgrade(r.configFactory.Build(nil))
+	upgrade.Force = r.force
+	upgrade.Timeout = timeoutForAction(r, req.Object)
+
+	latest := req.Object.Status.History.Latest()
+	if latest == nil {
+		return fmt.Errorf("upgrade failed: no release name recorded in history")
+	}
+
+	rls, err := upgrade.RunWithContext(ctx, latest.Name, req.Chart, req.Values)
+	if err != nil {
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	req.Object.Status.LastAttemptedReleaseAction = "upgrade"
+	req.Object.Status.History = append(v2.Snapshots{{
+		Name:         rls.Name,
+		Namespace:    rls.Namespace,
+		Version:      rls.Version,
+		ChartVersion: rls.Chart.Metadata.Version,
+		Status:       string(rls.Info.Status),
+	}}, req.Object.Status.History...)
+
+	// A prior failed or superseded release (e.g. one this force upgrade just
+	// recovered) may have left ReleasedCondition set to False. Mark it True
+	// now that this upgrade has succeeded, so Ready does not keep reflecting
+	// that stale failure.
+	conditions.MarkTrue(req.Object, v2.ReleasedCondition, meta.SucceededReason,
+		"Helm upgrade succeeded for release %s", req.Object.Status.History.Latest().FullReleaseName())
+
+	return nil
+}
+
+func (r *Upgrade) Name() string {
+	return "upgrade"
+}
+
+func (r *Upgrade) Type() ReconcilerType {
+	return ReconcilerTypeRelease
+}